@@ -0,0 +1,214 @@
+package fs
+
+import (
+	"container/list"
+	"expvar"
+	"io"
+	"sync"
+)
+
+// blockCacheBlockSize is the unit BlockCache fetches and caches,
+// matching CachingFs's default so the two can be swapped for each
+// other in front of the same backend.
+const blockCacheBlockSize = defaultBlockSize
+
+var (
+	blockCacheHits   = expvar.NewInt("fs.blockcache.hits")
+	blockCacheMisses = expvar.NewInt("fs.blockcache.misses")
+)
+
+// blockCacheKey identifies one block of one version of a file: two
+// Opens of the same path see the same cached bytes only if the file's
+// mtime (from Stat) hasn't changed in between, so a cache entry never
+// outlives the file version it was read from.
+type blockCacheKey struct {
+	name  string
+	mtime int64
+	index int64
+}
+
+// BlockCache is a fixed-size in-memory LRU of fixed-size blocks, read
+// through from an underlying backend.  It exists for repeated reads
+// of the same shards (e.g. a training pipeline re-opening the same
+// file many times).  Unlike CachingFs, which keeps its blocks in files
+// on local disk, BlockCache keeps them in memory, so once warm it
+// serves those reads with no I/O at all; its blocks do not outlive
+// the process.
+type BlockCache struct {
+	under     Fs
+	size      int
+	blockSize int64
+
+	mu    sync.Mutex
+	lru   *list.List
+	elems map[blockCacheKey]*list.Element
+}
+
+type blockCacheEntry struct {
+	key  blockCacheKey
+	data []byte
+}
+
+// NewCachedFS returns a drop-in replacement for the package-level
+// Open, reading through an in-memory LRU of at most size blocks, each
+// blockCacheBlockSize bytes.
+func NewCachedFS(size int) *BlockCache {
+	return newBlockCache(DefaultFs, size, blockCacheBlockSize)
+}
+
+// newBlockCache is NewCachedFS with under and blockSize broken out,
+// so tests (and callers wrapping something other than DefaultFs) can
+// pick both explicitly.
+func newBlockCache(under Fs, size int, blockSize int64) *BlockCache {
+	return &BlockCache{
+		under:     under,
+		size:      size,
+		blockSize: blockSize,
+		lru:       list.New(),
+		elems:     map[blockCacheKey]*list.Element{},
+	}
+}
+
+func (c *BlockCache) mtimeOf(name string) (int64, error) {
+	fi, e := c.under.Stat(name)
+	if e != nil {
+		return 0, e
+	}
+	return fi.ModTime().Unix(), nil
+}
+
+func (c *BlockCache) get(key blockCacheKey) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.elems[key]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*blockCacheEntry).data, true
+	}
+	return nil, false
+}
+
+func (c *BlockCache) put(key blockCacheKey, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.elems[key]; ok {
+		c.lru.MoveToFront(e)
+		e.Value.(*blockCacheEntry).data = data
+		return
+	}
+	c.elems[key] = c.lru.PushFront(&blockCacheEntry{key: key, data: data})
+	for c.size > 0 && c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		delete(c.elems, oldest.Value.(*blockCacheEntry).key)
+		c.lru.Remove(oldest)
+	}
+}
+
+// block returns the cached bytes of block index of name, fetching it
+// from c.under on a miss.
+func (c *BlockCache) block(name string, mtime, index int64) ([]byte, error) {
+	key := blockCacheKey{name: name, mtime: mtime, index: index}
+	if b, ok := c.get(key); ok {
+		blockCacheHits.Add(1)
+		return b, nil
+	}
+
+	blockCacheMisses.Add(1)
+	r, e := c.openRange(name, index*c.blockSize, c.blockSize)
+	if e != nil {
+		return nil, e
+	}
+	defer r.Close()
+	buf := make([]byte, c.blockSize)
+	n, e := io.ReadFull(r, buf)
+	if e != nil && e != io.ErrUnexpectedEOF && e != io.EOF {
+		return nil, e
+	}
+	buf = buf[:n]
+	c.put(key, buf)
+	return buf, nil
+}
+
+// openRange reads a bounded range from c.under, the same fallback
+// CachingFs.openRange uses.
+func (c *BlockCache) openRange(name string, off, n int64) (io.ReadCloser, error) {
+	if rb, ok := c.under.(RangedFs); ok {
+		return rb.OpenRangeAt(name, off, n)
+	}
+	r, e := c.under.Open(name)
+	if e != nil {
+		return nil, e
+	}
+	if off > 0 {
+		if _, e := io.CopyN(ioutilDiscard{}, r, off); e != nil {
+			r.Close()
+			return nil, e
+		}
+	}
+	return &limitReadCloser{r: r, n: n}, nil
+}
+
+// Open returns a cache-backed reader over name, fetched one block at
+// a time as the caller reads through it.
+func (c *BlockCache) Open(name string) (io.ReadCloser, error) {
+	mtime, e := c.mtimeOf(name)
+	if e != nil {
+		return nil, e
+	}
+	fi, e := c.under.Stat(name)
+	if e != nil {
+		return nil, e
+	}
+	return &blockCacheReader{c: c, name: name, mtime: mtime, size: fi.Size()}, nil
+}
+
+// Prefetch fills the blocks spanning [offset, offset+length) in the
+// background, so a caller that knows it will soon read that range
+// doesn't pay the miss latency inline.
+func (c *BlockCache) Prefetch(name string, offset, length int64) {
+	go func() {
+		mtime, e := c.mtimeOf(name)
+		if e != nil {
+			return
+		}
+		first := offset / c.blockSize
+		last := (offset + length - 1) / c.blockSize
+		for i := first; i <= last; i++ {
+			c.block(name, mtime, i)
+		}
+	}()
+}
+
+type blockCacheReader struct {
+	c      *BlockCache
+	name   string
+	mtime  int64
+	size   int64
+	offset int64
+	block  []byte
+}
+
+func (r *blockCacheReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	index := r.offset / r.c.blockSize
+	if r.block == nil {
+		b, e := r.c.block(r.name, r.mtime, index)
+		if e != nil {
+			return 0, e
+		}
+		r.block = b
+	}
+	within := r.offset % r.c.blockSize
+	if within >= int64(len(r.block)) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.block[within:])
+	r.offset += int64(n)
+	if r.offset%r.c.blockSize == 0 {
+		r.block = nil
+	}
+	return n, nil
+}
+
+func (r *blockCacheReader) Close() error { return nil }
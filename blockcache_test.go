@@ -0,0 +1,61 @@
+package fs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockCache(t *testing.T) {
+	assert := assert.New(t)
+
+	name := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs/%v/block.txt", time.Now().UnixNano())
+	w, e := Create(name)
+	assert.Nil(e)
+	fmt.Fprint(w, "0123456789")
+	w.Close()
+
+	c := newBlockCache(DefaultFs, 4, 4 /* tiny block size for the test */)
+
+	r, e := c.Open(name)
+	assert.Nil(e)
+	b, e := ioutil.ReadAll(r)
+	assert.Nil(e)
+	r.Close()
+	assert.Equal("0123456789", string(b))
+	missesAfterFirstRead := blockCacheMisses.Value()
+	assert.True(missesAfterFirstRead > 0)
+
+	r, e = c.Open(name)
+	assert.Nil(e)
+	b, e = ioutil.ReadAll(r)
+	assert.Nil(e)
+	r.Close()
+	assert.Equal("0123456789", string(b))
+	assert.Equal(missesAfterFirstRead, blockCacheMisses.Value()) // fully served from cache
+	assert.True(blockCacheHits.Value() > 0)
+}
+
+func TestBlockCachePrefetch(t *testing.T) {
+	assert := assert.New(t)
+
+	name := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs/%v/prefetch.txt", time.Now().UnixNano())
+	w, e := Create(name)
+	assert.Nil(e)
+	fmt.Fprint(w, "0123456789")
+	w.Close()
+
+	c := newBlockCache(DefaultFs, 4, 4)
+	mtime, e := c.mtimeOf(name)
+	assert.Nil(e)
+
+	c.Prefetch(name, 0, 10)
+	time.Sleep(50 * time.Millisecond) // let the background fetch land
+
+	if _, ok := c.get(blockCacheKey{name: name, mtime: mtime, index: 0}); !ok {
+		t.Fatal("expected Prefetch to have populated block 0")
+	}
+}
@@ -0,0 +1,247 @@
+package fs
+
+import (
+	"container/list"
+	"crypto/sha1"
+	"expvar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// defaultBlockSize is the unit CachingFs fetches, caches, and evicts
+// in, mirroring the block-cache pattern used in front of Keep
+// (keep-web) and other systems that front remote storage with a
+// read-through disk cache.
+const defaultBlockSize = 64 << 20 // 64 MiB
+
+var (
+	cachingFsHits        = expvar.NewInt("fs.cachingfs.hits")
+	cachingFsMisses       = expvar.NewInt("fs.cachingfs.misses")
+	cachingFsBytesFetched = expvar.NewInt("fs.cachingfs.bytesFetched")
+)
+
+// CachingFs wraps another Fs with a read-through block cache stored
+// under dir on local disk.  Reads are served a fixed-size block at a
+// time (blockSize), consulting the cache first and falling back to
+// the underlying backend on a miss; writes invalidate any cached
+// blocks for the file being written. An LRU policy evicts the
+// least-recently-used block once the cache exceeds maxBytes.
+type CachingFs struct {
+	under     Fs
+	dir       string
+	blockSize int64
+	maxBytes  int64
+
+	mu    sync.Mutex
+	lru   *list.List               // of cacheBlockKey, most-recently-used at the front
+	elems map[cacheBlockKey]*list.Element
+	size  int64
+}
+
+type cacheBlockKey struct {
+	name  string
+	index int64
+}
+
+// NewCachingFs returns an Fs that caches fixed-size blocks of under's
+// reads on local disk under dir, evicting the least-recently-used
+// block once more than maxBytes accumulates.  blockSize <= 0 selects
+// defaultBlockSize.
+func NewCachingFs(under Fs, dir string, maxBytes int64, blockSize int64) Fs {
+	if blockSize <= 0 {
+		blockSize = defaultBlockSize
+	}
+	return &CachingFs{
+		under:     under,
+		dir:       dir,
+		blockSize: blockSize,
+		maxBytes:  maxBytes,
+		lru:       list.New(),
+		elems:     map[cacheBlockKey]*list.Element{},
+	}
+}
+
+func (c *CachingFs) blockPath(key cacheBlockKey) string {
+	h := sha1.Sum([]byte(key.name))
+	return filepath.Join(c.dir, fmt.Sprintf("%x-%d", h, key.index))
+}
+
+// touch moves key to the front of the LRU list, inserting it if new.
+func (c *CachingFs) touch(key cacheBlockKey, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.elems[key]; ok {
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.elems[key] = c.lru.PushFront(key)
+	c.size += size
+
+	for c.maxBytes > 0 && c.size > c.maxBytes && c.lru.Len() > 0 {
+		oldest := c.lru.Back()
+		k := oldest.Value.(cacheBlockKey)
+		if fi, e := os.Stat(c.blockPath(k)); e == nil {
+			c.size -= fi.Size()
+		}
+		os.Remove(c.blockPath(k))
+		c.lru.Remove(oldest)
+		delete(c.elems, k)
+	}
+}
+
+// fetchBlock returns the bytes of block index of name, fetching and
+// caching it from c.under on a miss.
+func (c *CachingFs) fetchBlock(name string, index int64) ([]byte, error) {
+	key := cacheBlockKey{name: name, index: index}
+	blockFile := c.blockPath(key)
+
+	if b, e := ioutil.ReadFile(blockFile); e == nil {
+		cachingFsHits.Add(1)
+		c.touch(key, int64(len(b)))
+		return b, nil
+	}
+
+	cachingFsMisses.Add(1)
+	r, e := c.openRange(name, index*c.blockSize, c.blockSize)
+	if e != nil {
+		return nil, e
+	}
+	defer r.Close()
+	b, e := ioutil.ReadAll(r)
+	if e != nil {
+		return nil, e
+	}
+	cachingFsBytesFetched.Add(int64(len(b)))
+
+	if e := os.MkdirAll(c.dir, 0755); e == nil {
+		ioutil.WriteFile(blockFile, b, 0644)
+	}
+	c.touch(key, int64(len(b)))
+	return b, nil
+}
+
+// openRange reads a bounded range from c.under, using its RangedFs
+// implementation when available and falling back to a full Open plus
+// a bounded copy otherwise, the same fallback OpenRange uses.
+func (c *CachingFs) openRange(name string, off, n int64) (io.ReadCloser, error) {
+	if rb, ok := c.under.(RangedFs); ok {
+		return rb.OpenRangeAt(name, off, n)
+	}
+	r, e := c.under.Open(name)
+	if e != nil {
+		return nil, e
+	}
+	if off > 0 {
+		if _, e := io.CopyN(ioutilDiscard{}, r, off); e != nil {
+			r.Close()
+			return nil, e
+		}
+	}
+	return &limitReadCloser{r: r, n: n}, nil
+}
+
+// invalidate drops every cached block for name, e.g. after a write.
+func (c *CachingFs) invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.elems {
+		if k.name == name {
+			if fi, e := os.Stat(c.blockPath(k)); e == nil {
+				c.size -= fi.Size()
+			}
+			os.Remove(c.blockPath(k))
+			c.lru.Remove(e)
+			delete(c.elems, k)
+		}
+	}
+}
+
+func (c *CachingFs) Create(name string) (io.WriteCloser, error) {
+	c.invalidate(name)
+	return c.under.Create(name)
+}
+
+// Open returns a cache-backed reader over the whole file, fetched one
+// block at a time as the caller reads through it.
+func (c *CachingFs) Open(name string) (io.ReadCloser, error) {
+	fi, e := c.under.Stat(name)
+	if e != nil {
+		return nil, e
+	}
+	return &cachingReader{c: c, name: name, size: fi.Size()}, nil
+}
+
+// cachingReader sequentially serves fetchBlock results, so a caller
+// reading the whole file touches the cache once per block instead of
+// once per Read call.
+type cachingReader struct {
+	c      *CachingFs
+	name   string
+	size   int64
+	offset int64
+	block  []byte
+}
+
+func (r *cachingReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	index := r.offset / r.c.blockSize
+	if r.block == nil {
+		b, e := r.c.fetchBlock(r.name, index)
+		if e != nil {
+			return 0, e
+		}
+		r.block = b
+	}
+	within := r.offset % r.c.blockSize
+	if within >= int64(len(r.block)) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.block[within:])
+	r.offset += int64(n)
+	if r.offset%r.c.blockSize == 0 {
+		r.block = nil // force a fetchBlock for the next block
+	}
+	return n, nil
+}
+
+func (r *cachingReader) Close() error { return nil }
+
+func (c *CachingFs) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	c.invalidate(name)
+	return c.under.OpenFile(name, flag, perm)
+}
+
+func (c *CachingFs) Stat(name string) (os.FileInfo, error)        { return c.under.Stat(name) }
+func (c *CachingFs) ReadDir(name string) ([]os.FileInfo, error)   { return c.under.ReadDir(name) }
+func (c *CachingFs) Mkdir(name string, perm os.FileMode) error    { return c.under.Mkdir(name, perm) }
+func (c *CachingFs) MkdirAll(name string, perm os.FileMode) error { return c.under.MkdirAll(name, perm) }
+
+func (c *CachingFs) Remove(name string) error {
+	c.invalidate(name)
+	return c.under.Remove(name)
+}
+
+func (c *CachingFs) RemoveAll(name string) error {
+	c.invalidate(name)
+	return c.under.RemoveAll(name)
+}
+
+func (c *CachingFs) Rename(oldname, newname string) error {
+	c.invalidate(oldname)
+	c.invalidate(newname)
+	return c.under.Rename(oldname, newname)
+}
+
+func (c *CachingFs) Chmod(name string, mode os.FileMode) error { return c.under.Chmod(name, mode) }
+
+func (c *CachingFs) Chtimes(name string, atime, mtime time.Time) error {
+	return c.under.Chtimes(name, atime, mtime)
+}
@@ -0,0 +1,47 @@
+package fs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachingFs(t *testing.T) {
+	assert := assert.New(t)
+
+	srcDir, e := ioutil.TempDir("", "fs-cachingfs-src")
+	assert.Nil(e)
+	defer os.RemoveAll(srcDir)
+	cacheDir, e := ioutil.TempDir("", "fs-cachingfs-cache")
+	assert.Nil(e)
+	defer os.RemoveAll(cacheDir)
+
+	under := rootedLocalFs(srcDir)
+	c := NewCachingFs(under, cacheDir, 0, 4 /* tiny block size for the test */)
+
+	name := "/hello.txt"
+	w, e := c.Create(name)
+	assert.Nil(e)
+	fmt.Fprint(w, "0123456789")
+	w.Close()
+
+	r, e := c.Open(name)
+	assert.Nil(e)
+	b, e := ioutil.ReadAll(r)
+	assert.Nil(e)
+	r.Close()
+	assert.Equal("0123456789", string(b))
+	assert.True(cachingFsMisses.Value() > 0)
+
+	hitsBefore := cachingFsHits.Value()
+	r, e = c.Open(name)
+	assert.Nil(e)
+	b, e = ioutil.ReadAll(r)
+	assert.Nil(e)
+	r.Close()
+	assert.Equal("0123456789", string(b))
+	assert.True(cachingFsHits.Value() > hitsBefore) // second read serves from the block cache
+}
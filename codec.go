@@ -0,0 +1,169 @@
+package fs
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec encodes and decodes a Go value to and from a byte stream.
+// SaveAs and LoadAs use it in place of the gob format Save/Load used
+// to hard-code, so artifacts can be written in a form other tools
+// (or other languages) can read.
+type Codec interface {
+	Encode(w io.Writer, data interface{}) error
+	Decode(r io.Reader, data interface{}) error
+}
+
+// GobCodec is the format Save/Load used exclusively before SaveAs
+// existed, and remains the default for filenames with no codec
+// suffix, so existing callers and on-disk files keep working.
+type GobCodec struct{}
+
+func (GobCodec) Encode(w io.Writer, data interface{}) error {
+	return gob.NewEncoder(w).Encode(data)
+}
+
+func (GobCodec) Decode(r io.Reader, data interface{}) error {
+	return gob.NewDecoder(r).Decode(data)
+}
+
+// JSONCodec selected by the ".json" filename suffix.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(w io.Writer, data interface{}) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+func (JSONCodec) Decode(r io.Reader, data interface{}) error {
+	return json.NewDecoder(r).Decode(data)
+}
+
+// ProtoCodec selected by the ".pb" filename suffix. It requires data
+// to implement proto.Message; Save falls back to GobCodec for
+// filenames ending in ".pb" whose data doesn't.
+type ProtoCodec struct{}
+
+func (ProtoCodec) Encode(w io.Writer, data interface{}) error {
+	m, ok := data.(proto.Message)
+	if !ok {
+		return fmt.Errorf("fs: ProtoCodec.Encode: %T does not implement proto.Message", data)
+	}
+	b, e := proto.Marshal(m)
+	if e != nil {
+		return e
+	}
+	_, e = w.Write(b)
+	return e
+}
+
+func (ProtoCodec) Decode(r io.Reader, data interface{}) error {
+	m, ok := data.(proto.Message)
+	if !ok {
+		return fmt.Errorf("fs: ProtoCodec.Decode: %T does not implement proto.Message", data)
+	}
+	b, e := ioutil.ReadAll(r)
+	if e != nil {
+		return e
+	}
+	return proto.Unmarshal(b, m)
+}
+
+// Compression names the optional stream wrapping SaveAs/LoadAs apply
+// around a Codec's bytes.
+type Compression int
+
+const (
+	// NoCompression writes the codec's bytes as is.
+	NoCompression Compression = iota
+	// Gzip wraps the codec's bytes in compress/gzip, selected by the
+	// ".gz" filename suffix.
+	Gzip
+	// Zstd wraps the codec's bytes in klauspost/compress/zstd,
+	// selected by the ".zst" filename suffix.
+	Zstd
+)
+
+// Option configures SaveAs/LoadAs, overriding what their filename
+// suffix would otherwise select.
+type Option func(*codecOptions)
+
+type codecOptions struct {
+	compression Compression
+}
+
+// WithCompression overrides the compression SaveAs/LoadAs would
+// otherwise infer from the filename suffix.
+func WithCompression(c Compression) Option {
+	return func(o *codecOptions) {
+		o.compression = c
+	}
+}
+
+// compressionForSuffix returns the Compression implied by name's
+// outermost suffix, and the name with that suffix stripped.
+func compressionForSuffix(name string) (Compression, string) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".gz":
+		return Gzip, strings.TrimSuffix(name, path.Ext(name))
+	case ".zst":
+		return Zstd, strings.TrimSuffix(name, path.Ext(name))
+	default:
+		return NoCompression, name
+	}
+}
+
+// codecForSuffix returns the Codec implied by name's (post-compression)
+// suffix, defaulting to GobCodec. A ".pb" suffix only selects
+// ProtoCodec when data implements proto.Message, so callers saving a
+// plain struct to a ".pb" file (e.g. from an older convention) still
+// round-trip through gob.
+func codecForSuffix(name string, data interface{}) Codec {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return JSONCodec{}
+	case ".pb":
+		if _, ok := data.(proto.Message); ok {
+			return ProtoCodec{}
+		}
+	}
+	return GobCodec{}
+}
+
+func compressWriter(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+func decompressReader(r io.Reader, c Compression) (io.ReadCloser, error) {
+	switch c {
+	case Gzip:
+		return gzip.NewReader(r)
+	case Zstd:
+		d, e := zstd.NewReader(r)
+		if e != nil {
+			return nil, e
+		}
+		return d.IOReadCloser(), nil
+	default:
+		return ioutil.NopCloser(r), nil
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,225 @@
+package fs
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/vladimirvivien/gowfs"
+)
+
+// CreateContext is like Create but aborts the underlying write when
+// ctx is done.  Today's Create spins an unrecoverable goroutine that
+// log.Panicf's on error with no way to cancel it; CreateContext fixes
+// both problems by reporting failures through the returned
+// WriteCloser's Close instead of panicking, and by closing the
+// in-flight write as soon as ctx is done.
+func CreateContext(ctx context.Context, name string) (io.WriteCloser, error) {
+	b, p := backendFor(name)
+	if wb, ok := b.(webBackend); ok {
+		return wb.CreateContext(ctx, p)
+	}
+
+	w, e := b.Create(p)
+	if e != nil {
+		return nil, e
+	}
+	if _, ok := b.(hdfsBackend); ok {
+		closeOnDone(ctx, w)
+	}
+	return ctxWriteCloser{ctx: ctx, w: w}, nil
+}
+
+// closeOnDone spawns a goroutine that closes c as soon as ctx is
+// done, the mechanism colinmarc/hdfs recommends for aborting an
+// in-flight RPC: the client has no native per-call context, so
+// closing the connection is what actually stops it from hanging.
+func closeOnDone(ctx context.Context, c io.Closer) {
+	go func() {
+		<-ctx.Done()
+		c.Close()
+	}()
+}
+
+// ctxWriteCloser wraps a WriteCloser so that Write and Close return
+// ctx.Err() once ctx is done, for backends with no native
+// cancellation hook.
+type ctxWriteCloser struct {
+	ctx context.Context
+	w   io.WriteCloser
+}
+
+func (c ctxWriteCloser) Write(p []byte) (int, error) {
+	if e := c.ctx.Err(); e != nil {
+		return 0, e
+	}
+	return c.w.Write(p)
+}
+
+func (c ctxWriteCloser) Close() error {
+	e := c.w.Close()
+	if ce := c.ctx.Err(); ce != nil {
+		return ce
+	}
+	return e
+}
+
+// OpenContext is like Open but cancels the in-flight request when ctx
+// is done.
+func OpenContext(ctx context.Context, name string) (io.ReadCloser, error) {
+	b, p := backendFor(name)
+	if wb, ok := b.(webBackend); ok {
+		return wb.OpenContext(ctx, p)
+	}
+	r, e := b.Open(p)
+	if e != nil {
+		return nil, e
+	}
+	if _, ok := b.(hdfsBackend); ok {
+		closeOnDone(ctx, r)
+	}
+	return r, nil
+}
+
+// waitOrCancel runs fn in a goroutine and returns its error, unless
+// ctx is done first, in which case it returns ctx.Err() without
+// waiting for fn to finish. Neither rpcfs's blocking RPCs nor gowfs's
+// blocking HTTP calls expose a way to actually abort an in-flight
+// call that isn't streaming a Reader/Writer we can close out from
+// under it (see closeOnDone for that case), so this only stops the
+// caller from waiting on one; fn keeps running in the background.
+func waitOrCancel(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case e := <-done:
+		return e
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StatContext is like Stat but cancels the in-flight request when ctx
+// is done. See waitOrCancel for what "cancels" means here: rpcfs.Stat
+// and webfs.GetFileStatus have no native cancellation hook, so
+// StatContext stops waiting on them rather than aborting them.
+func StatContext(ctx context.Context, name string) (os.FileInfo, error) {
+	b, p := backendFor(name)
+	var fi os.FileInfo
+	e := waitOrCancel(ctx, func() error {
+		var statErr error
+		fi, statErr = b.Stat(p)
+		return statErr
+	})
+	if e != nil {
+		return nil, e
+	}
+	return fi, nil
+}
+
+// ReadDirContext is like ReadDir but cancels the in-flight request
+// when ctx is done. See StatContext.
+func ReadDirContext(ctx context.Context, name string) ([]os.FileInfo, error) {
+	b, p := backendFor(name)
+	var ls []os.FileInfo
+	e := waitOrCancel(ctx, func() error {
+		var readErr error
+		ls, readErr = b.ReadDir(p)
+		return readErr
+	})
+	if e != nil {
+		return nil, e
+	}
+	return ls, nil
+}
+
+// MkdirContext is like Mkdir but cancels the in-flight request when
+// ctx is done. See StatContext.
+func MkdirContext(ctx context.Context, name string) error {
+	b, p := backendFor(name)
+	return waitOrCancel(ctx, func() error {
+		return b.MkdirAll(p, 0777)
+	})
+}
+
+// PutContext is like Put but returns ctx.Err() as soon as ctx is
+// done.  The upload itself is not aborted: Put keeps running in the
+// background until it finishes on its own.
+func PutContext(ctx context.Context, localFile, hdfsPath string) (bool, error) {
+	done := make(chan struct{})
+	var ok bool
+	var e error
+	go func() {
+		ok, e = Put(localFile, hdfsPath)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return ok, e
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// CreateContext pipes data to webfs.Create in a background goroutine,
+// same as the plain Create, but reports the goroutine's error through
+// the returned WriteCloser's Close instead of log.Panicf'ing, and
+// closes the pipe as soon as ctx is done: gowfs has no per-request
+// context hook, but closing the Reader its blocking http.Client.Do is
+// reading from aborts the upload client-side.
+func (webBackend) CreateContext(ctx context.Context, name string) (io.WriteCloser, error) {
+	if webfs == nil {
+		return nil, errNoWebFS
+	}
+	r, w := io.Pipe()
+	closeOnDone(ctx, r)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, e := webfs.Create(r, gowfs.Path{Name: name}, true, 0, 0, 0700, 0)
+		errc <- e
+		r.Close()
+	}()
+	return ctxPipeWriter{ctx: ctx, w: w, errc: errc}, nil
+}
+
+// ctxPipeWriter propagates the WebHDFS goroutine's error, as well as
+// ctx's cancellation, back through Close instead of panicking.
+type ctxPipeWriter struct {
+	ctx  context.Context
+	w    *io.PipeWriter
+	errc chan error
+}
+
+func (c ctxPipeWriter) Write(p []byte) (int, error) {
+	if e := c.ctx.Err(); e != nil {
+		return 0, e
+	}
+	return c.w.Write(p)
+}
+
+func (c ctxPipeWriter) Close() error {
+	c.w.Close()
+	select {
+	case e := <-c.errc:
+		return e
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+}
+
+// OpenContext is like the plain Open, but closes the response body as
+// soon as ctx is done, which aborts the in-flight GET: gowfs returns
+// the HTTP response body directly as the ReadCloser, so closing it is
+// the real cancellation hook, not a field on gowfs.Configuration.
+func (webBackend) OpenContext(ctx context.Context, name string) (io.ReadCloser, error) {
+	if webfs == nil {
+		return nil, errNoWebFS
+	}
+	r, e := webfs.Open(gowfs.Path{Name: name}, 0, 0, 0)
+	if e != nil {
+		return nil, e
+	}
+	closeOnDone(ctx, r)
+	return r, nil
+}
@@ -0,0 +1,64 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateContextCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	name := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs/%v/ctx.txt", time.Now().UnixNano())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w, e := CreateContext(ctx, name)
+	assert.Nil(e)
+
+	_, e = w.Write([]byte("too late"))
+	assert.Equal(context.Canceled, e)
+	assert.Equal(context.Canceled, w.Close())
+}
+
+func TestCreateContextDeadline(t *testing.T) {
+	assert := assert.New(t)
+
+	name := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs/%v/ctx-deadline.txt", time.Now().UnixNano())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	time.Sleep(5 * time.Millisecond)
+
+	w, e := CreateContext(ctx, name)
+	assert.Nil(e)
+
+	_, e = w.Write([]byte("too late"))
+	assert.Equal(context.DeadlineExceeded, e)
+}
+
+// TestCloseOnDoneReturnsPromptly stands in for the
+// colinmarc/hdfs RPC cancellation path, which this sandbox cannot
+// exercise against a real cluster: it asserts that a blocking Read
+// unblocks promptly once ctx.Done fires, rather than hanging until
+// the peer responds.
+func TestCloseOnDoneReturnsPromptly(t *testing.T) {
+	assert := assert.New(t)
+
+	r, w := io.Pipe()
+	defer w.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	closeOnDone(ctx, r)
+
+	start := time.Now()
+	_, e := r.Read(make([]byte, 1)) // would block forever without closeOnDone
+	assert.NotNil(e)
+	assert.True(time.Since(start) < time.Second, "Read should return promptly once ctx is done")
+}
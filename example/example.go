@@ -31,7 +31,7 @@ func main() {
 	if w, e := fs.Create(file); e != nil {
 		log.Panicf("Create(%v) failed: %v", file, e)
 	} else {
-		fmt.Fprintf(w, content)
+		fmt.Fprintf(w, "%s", content)
 		w.Close()
 	}
 
@@ -79,6 +79,11 @@ func (i FileInfo) Sys() interface{} {
 var (
 	webfs *gowfs.FileSystem
 	rpcfs *hdfs.Client
+
+	// rpcNamenode remembers the address rpcfs was dialed with, since
+	// *hdfs.Client keeps it in an unexported field. WithUser needs it
+	// to open a second, impersonated connection to the same namenode.
+	rpcNamenode string
 )
 
 func HookupHDFS(namenode, webapi, role string) error {
@@ -97,6 +102,7 @@ func HookupHDFS(namenode, webapi, role string) error {
 		err += fmt.Sprintf("Cannot estabilish RPC connection to %s@%s: %v", role, namenode, e)
 	} else {
 		rpcfs = fs
+		rpcNamenode = namenode
 	}
 
 	log.Printf("Establish WebHDFS connection as %s@%s", role, webapi)
@@ -110,7 +116,7 @@ func HookupHDFS(namenode, webapi, role string) error {
 	}
 
 	if len(err) > 0 {
-		return fmt.Errorf(err)
+		return fmt.Errorf("%s", err)
 	}
 	return nil
 }
@@ -129,123 +135,507 @@ var (
 	errNoRpcFS = errors.New("Have not established protobuf-based RPC connection")
 )
 
-// Create returns the writer end of a Go pipe and starts a goroutine
-// that copies from the reader end of the pipe to either a local file
-// or an HDFS file.  If Create returns without error, the caller is
-// expected to write into the returned writer end.  After writing, the
-// caller must close the writer end to acknowledge the EOF.
-func Create(name string) (io.WriteCloser, error) {
-	switch fs, path := FsPath(name); fs {
-	case WebFS:
-		if webfs == nil {
-			return nil, errNoWebFS
-		}
-		// gowfs.Create requires a reader parameter.
-		r, w := io.Pipe()
-		go func() {
-			_, e := webfs.Create(r,
-				gowfs.Path{Name: path},
-				true, // overwrite
-				0, 0, // default blocksize and replica
-				0700, // only the owner can access
-				0)    // default buffer size
-			if e != nil {
-				r.Close()
-				w.Close()
-				log.Panicf("Failed piping to file %s: %v", name, e)
-			}
-		}()
-		return w, nil
-	case HDFS:
-		if rpcfs == nil {
-			return nil, errNoRpcFS
+// ErrInvalidArgument is returned by Rename when old is an ancestor of
+// new, e.g. renaming "/a" to "/a/b/c" — the classic unionfs/POSIX
+// "rename a to a descendant of itself" cycle.
+var ErrInvalidArgument = errors.New("fs: invalid argument")
+
+// isAncestor reports whether old is an ancestor of (or equal to) new,
+// i.e. new would become a descendant of old after the rename.
+func isAncestor(old, new string) bool {
+	old = strings.TrimSuffix(old, "/")
+	return new == old || strings.HasPrefix(new, old+"/")
+}
+
+// ErrNotImplemented is returned by backend methods that a given
+// backend does not (yet) support.
+var ErrNotImplemented = errors.New("fs: not implemented by this backend")
+
+// Fs is the interface implemented by every storage backend registered
+// with Register.  It is modeled after spf13/afero.Fs, trimmed to what
+// this package actually needs: backends stream through plain
+// io.WriteCloser/io.ReadCloser rather than an afero.File, since every
+// existing caller only ever reads or writes sequentially.
+type Fs interface {
+	Create(name string) (io.WriteCloser, error)
+	Open(name string) (io.ReadCloser, error)
+	OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error)
+	Stat(name string) (os.FileInfo, error)
+	ReadDir(name string) ([]os.FileInfo, error)
+	Mkdir(name string, perm os.FileMode) error
+	MkdirAll(name string, perm os.FileMode) error
+	Remove(name string) error
+	RemoveAll(name string) error
+	Rename(oldname, newname string) error
+	Chmod(name string, mode os.FileMode) error
+	Chtimes(name string, atime, mtime time.Time) error
+}
+
+// backends maps a path prefix, such as "/s3/" or "/hdfs/", to the Fs
+// implementing it.  Register adds new entries; DefaultFs consults it
+// to dispatch each call, so adding a backend no longer requires
+// touching FsPath or any of the functions below.
+var backends = map[string]Fs{}
+
+// Register associates prefix (e.g. "/s3/") with a backend.  Paths
+// passed to the package-level Create/Open/... functions that start
+// with prefix are routed to b; everything else falls through to the
+// local filesystem.
+func Register(prefix string, b Fs) {
+	backends[prefix] = b
+}
+
+func init() {
+	Register("/inmem/", inMemBackend{})
+	Register("/hdfs/", hdfsBackend{})
+	Register("/webfs/", webBackend{})
+
+	// TODO(wyi): HookupGCS replaces this placeholder with a real
+	// cloud.google.com/go/storage-backed Fs once called; /sftp/ and
+	// /azure/ still only reserve their prefixes, pending pkg/sftp and
+	// an Azure Blob Storage backend.
+	Register("/gs/", unimplementedBackend{name: "gs"})
+	Register("/sftp/", unimplementedBackend{name: "sftp"})
+	Register("/azure/", unimplementedBackend{name: "azure"})
+}
+
+// backendFor returns the Fs registered for name's prefix, along with
+// the path local to that backend.  Paths matching no registered
+// prefix are served by the local filesystem.
+func backendFor(name string) (Fs, string) {
+	for prefix, b := range backends {
+		if strings.HasPrefix(name, prefix) {
+			return b, "/" + strings.TrimPrefix(name, prefix)
 		}
-		return rpcfs.Create(path)
-	case InMem:
-		return DefaultInMemFS.Create(path), nil
-	default:
-		return os.Create(path)
 	}
+	return localBackend{}, name
 }
 
-func Open(name string) (io.ReadCloser, error) {
-	switch fs, path := FsPath(name); fs {
-	case WebFS:
-		if webfs == nil {
-			return nil, errNoWebFS
-		}
-		return webfs.Open(gowfs.Path{Name: path}, 0, 0, 0) // default offset, lenght and buffersize
-	case HDFS:
-		if rpcfs == nil {
-			return nil, errNoRpcFS
-		}
-		return rpcfs.Open(path)
-	case InMem:
-		return DefaultInMemFS.Open(path)
-	default:
-		return os.Open(path)
+// DefaultFs is the Fs that the package-level Create/Open/... functions
+// delegate to.  It dispatches each call to whichever backend is
+// registered for that call's path prefix.
+var DefaultFs Fs = dispatchFs{}
+
+// dispatchFs implements Fs by looking up the backend registered for
+// each call's path prefix.  It lets DefaultFs be handed to code (e.g.
+// UnionFS or CachingFs) that expects a single Fs value rather
+// than the package-level functions.
+type dispatchFs struct{}
+
+func (dispatchFs) Create(name string) (io.WriteCloser, error) {
+	b, p := backendFor(name)
+	return b.Create(p)
+}
+
+func (dispatchFs) Open(name string) (io.ReadCloser, error) {
+	b, p := backendFor(name)
+	return b.Open(p)
+}
+
+func (dispatchFs) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	b, p := backendFor(name)
+	return b.OpenFile(p, flag, perm)
+}
+
+func (dispatchFs) Stat(name string) (os.FileInfo, error) {
+	b, p := backendFor(name)
+	return b.Stat(p)
+}
+
+func (dispatchFs) ReadDir(name string) ([]os.FileInfo, error) {
+	b, p := backendFor(name)
+	return b.ReadDir(p)
+}
+
+func (dispatchFs) Mkdir(name string, perm os.FileMode) error {
+	b, p := backendFor(name)
+	return b.Mkdir(p, perm)
+}
+
+func (dispatchFs) MkdirAll(name string, perm os.FileMode) error {
+	b, p := backendFor(name)
+	return b.MkdirAll(p, perm)
+}
+
+func (dispatchFs) Remove(name string) error {
+	b, p := backendFor(name)
+	return b.Remove(p)
+}
+
+func (dispatchFs) RemoveAll(name string) error {
+	b, p := backendFor(name)
+	return b.RemoveAll(p)
+}
+
+// AllowCrossBackendRename enables a copy+delete fallback in Rename
+// when oldname and newname resolve to different backends (e.g.
+// "/hdfs/a" to "/inmem/b"). It defaults to false because the fallback
+// is not atomic: a failure between the copy and the delete can leave
+// both a source and a destination copy behind.
+var AllowCrossBackendRename = false
+
+func (dispatchFs) Rename(oldname, newname string) error {
+	if isAncestor(oldname, newname) {
+		return ErrInvalidArgument
+	}
+	ob, op := backendFor(oldname)
+	nb, np := backendFor(newname)
+	if ob == nb {
+		return ob.Rename(op, np)
 	}
+	if !AllowCrossBackendRename {
+		return fmt.Errorf("fs: Rename(%s, %s): cannot rename across backends (set fs.AllowCrossBackendRename to allow a non-atomic copy+delete)", oldname, newname)
+	}
+
+	r, e := ob.Open(op)
+	if e != nil {
+		return e
+	}
+	defer r.Close()
+	w, e := nb.Create(np)
+	if e != nil {
+		return e
+	}
+	if _, e := io.Copy(w, r); e != nil {
+		w.Close()
+		return e
+	}
+	if e := w.Close(); e != nil {
+		return e
+	}
+	return ob.Remove(op)
 }
 
-func ReadDir(name string) ([]os.FileInfo, error) {
-	switch fs, path := FsPath(name); fs {
-	case WebFS:
-		if webfs == nil {
-			return nil, errNoWebFS
-		}
+func (dispatchFs) Chmod(name string, mode os.FileMode) error {
+	b, p := backendFor(name)
+	return b.Chmod(p, mode)
+}
 
-		if ok, e := (&gowfs.FsShell{FileSystem: webfs, WorkingPath: "/"}).Exists(path); !ok {
-			return nil, &os.PathError{
-				Op:   "ReadDir",
-				Path: path,
-				Err:  os.ErrNotExist}
-		} else if e != nil {
-			return nil, e
-		}
+func (dispatchFs) Chtimes(name string, atime, mtime time.Time) error {
+	b, p := backendFor(name)
+	return b.Chtimes(p, atime, mtime)
+}
+
+// localBackend implements Fs on top of the os package.
+type localBackend struct{}
+
+func (localBackend) Create(name string) (io.WriteCloser, error) { return os.Create(name) }
+func (localBackend) Open(name string) (io.ReadCloser, error)     { return os.Open(name) }
+func (localBackend) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	return os.OpenFile(name, flag, perm)
+}
+func (localBackend) Stat(name string) (os.FileInfo, error)        { return os.Stat(name) }
+func (localBackend) ReadDir(name string) ([]os.FileInfo, error)   { return ioutil.ReadDir(name) }
+func (localBackend) Mkdir(name string, perm os.FileMode) error    { return os.Mkdir(name, perm) }
+func (localBackend) MkdirAll(name string, perm os.FileMode) error { return os.MkdirAll(name, perm) }
+func (localBackend) Remove(name string) error                     { return os.Remove(name) }
+func (localBackend) RemoveAll(name string) error                  { return os.RemoveAll(name) }
+func (localBackend) Rename(oldname, newname string) error         { return os.Rename(oldname, newname) }
+func (localBackend) Chmod(name string, mode os.FileMode) error    { return os.Chmod(name, mode) }
+func (localBackend) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
 
-		is, e := webfs.ListStatus(gowfs.Path{Name: path})
+// inMemBackend adapts DefaultInMemFS to the Fs interface.
+type inMemBackend struct{}
+
+func (inMemBackend) Create(name string) (io.WriteCloser, error) {
+	return DefaultInMemFS.Create(name), nil
+}
+func (inMemBackend) Open(name string) (io.ReadCloser, error) { return DefaultInMemFS.Open(name) }
+func (inMemBackend) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	return nil, ErrNotImplemented
+}
+func (inMemBackend) Stat(name string) (os.FileInfo, error)      { return DefaultInMemFS.Stat(name) }
+func (inMemBackend) ReadDir(name string) ([]os.FileInfo, error) { return DefaultInMemFS.ReadDir(name) }
+func (inMemBackend) Mkdir(name string, perm os.FileMode) error {
+	DefaultInMemFS.MkDir(name)
+	return nil
+}
+func (inMemBackend) MkdirAll(name string, perm os.FileMode) error {
+	DefaultInMemFS.MkDir(name)
+	return nil
+}
+func (inMemBackend) Remove(name string) error            { return DefaultInMemFS.Remove(name) }
+func (inMemBackend) RemoveAll(name string) error          { return DefaultInMemFS.RemoveAll(name) }
+func (inMemBackend) Rename(oldname, newname string) error { return DefaultInMemFS.Rename(oldname, newname) }
+func (inMemBackend) Chmod(name string, mode os.FileMode) error {
+	return DefaultInMemFS.Chmod(name, mode)
+}
+func (inMemBackend) Chtimes(name string, atime, mtime time.Time) error {
+	return DefaultInMemFS.Chtimes(name, atime, mtime)
+}
+
+// hdfsBackend adapts the protobuf-based rpcfs client to the Fs
+// interface.
+type hdfsBackend struct{}
+
+func (hdfsBackend) Create(name string) (io.WriteCloser, error) {
+	if rpcfs == nil {
+		return nil, errNoRpcFS
+	}
+	return rpcfs.Create(name)
+}
+func (hdfsBackend) Open(name string) (io.ReadCloser, error) {
+	if rpcfs == nil {
+		return nil, errNoRpcFS
+	}
+	return rpcfs.Open(name)
+}
+func (hdfsBackend) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	return nil, ErrNotImplemented
+}
+func (hdfsBackend) Stat(name string) (os.FileInfo, error) {
+	if rpcfs == nil {
+		return nil, errNoRpcFS
+	}
+	return rpcfs.Stat(name)
+}
+func (hdfsBackend) ReadDir(name string) ([]os.FileInfo, error) {
+	if rpcfs == nil {
+		return nil, errNoRpcFS
+	}
+	return rpcfs.ReadDir(name)
+}
+func (hdfsBackend) Mkdir(name string, perm os.FileMode) error {
+	if rpcfs == nil {
+		return errNoRpcFS
+	}
+	return rpcfs.MkdirAll(name, perm)
+}
+func (hdfsBackend) MkdirAll(name string, perm os.FileMode) error {
+	if rpcfs == nil {
+		return errNoRpcFS
+	}
+	return rpcfs.MkdirAll(name, perm)
+}
+func (hdfsBackend) Remove(name string) error {
+	if rpcfs == nil {
+		return errNoRpcFS
+	}
+	return rpcfs.Remove(name)
+}
+func (hdfsBackend) RemoveAll(name string) error {
+	if rpcfs == nil {
+		return errNoRpcFS
+	}
+	return rpcfs.Remove(name) // rpcfs.Remove is already recursive.
+}
+func (hdfsBackend) Rename(oldname, newname string) error {
+	if rpcfs == nil {
+		return errNoRpcFS
+	}
+	if isAncestor(oldname, newname) {
+		return ErrInvalidArgument
+	}
+	return rpcfs.Rename(oldname, newname)
+}
+func (hdfsBackend) Chmod(name string, mode os.FileMode) error {
+	if rpcfs == nil {
+		return errNoRpcFS
+	}
+	return rpcfs.Chmod(name, mode)
+}
+func (hdfsBackend) Chtimes(name string, atime, mtime time.Time) error {
+	if rpcfs == nil {
+		return errNoRpcFS
+	}
+	return rpcfs.Chtimes(name, atime, mtime)
+}
+
+// webBackend adapts the WebHDFS client (gowfs) to the Fs interface.
+type webBackend struct{}
+
+func (webBackend) Create(name string) (io.WriteCloser, error) {
+	if webfs == nil {
+		return nil, errNoWebFS
+	}
+	// gowfs.Create requires a reader parameter.
+	r, w := io.Pipe()
+	go func() {
+		_, e := webfs.Create(r,
+			gowfs.Path{Name: name},
+			true, // overwrite
+			0, 0, // default blocksize and replica
+			0700, // only the owner can access
+			0)    // default buffer size
 		if e != nil {
-			return nil, e
+			r.Close()
+			w.Close()
+			log.Panicf("Failed piping to file %s: %v", name, e)
 		}
+	}()
+	return w, nil
+}
+func (webBackend) Open(name string) (io.ReadCloser, error) {
+	if webfs == nil {
+		return nil, errNoWebFS
+	}
+	return webfs.Open(gowfs.Path{Name: name}, 0, 0, 0) // default offset, lenght and buffersize
+}
+func (webBackend) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	return nil, ErrNotImplemented
+}
+func (webBackend) Stat(name string) (os.FileInfo, error) {
+	if webfs == nil {
+		return nil, errNoWebFS
+	}
+	if fi, e := webfs.GetFileStatus(gowfs.Path{Name: name}); e != nil {
+		return nil, &os.PathError{
+			Op:   "Stat",
+			Path: name + e.Error(), // BUG(y): Hacky way to return the real error.
+			Err:  os.ErrNotExist}   //BUG(y): for whatever error, returns os.PathError.
+	} else {
+		mode, _ := strconv.ParseUint(fi.Permission, 8, 32)
+		return &FileInfo{
+			name: path.Base(name),
+			size: fi.Length,
+			mode: os.FileMode(mode),
+			time: fi.ModificationTime,
+			dir:  fi.Type == "DIRECTORY"}, nil
+	}
+}
+func (webBackend) ReadDir(name string) ([]os.FileInfo, error) {
+	if webfs == nil {
+		return nil, errNoWebFS
+	}
 
-		ss := make([]os.FileInfo, 0, len(is))
-		for _, s := range is {
-			mode, _ := strconv.ParseUint(s.Permission, 8, 32)
-			ss = append(ss, &FileInfo{
-				name: s.PathSuffix,
-				size: s.Length,
-				mode: os.FileMode(mode),
-				time: s.ModificationTime,
-				dir:  (s.Type == "DIRECTORY"),
-			})
-		}
-		return ss, nil
-	case HDFS:
-		return rpcfs.ReadDir(path)
-	case InMem:
-		return DefaultInMemFS.ReadDir(path)
-	default:
-		return ioutil.ReadDir(path)
+	if ok, e := (&gowfs.FsShell{FileSystem: webfs, WorkingPath: "/"}).Exists(name); !ok {
+		return nil, &os.PathError{
+			Op:   "ReadDir",
+			Path: name,
+			Err:  os.ErrNotExist}
+	} else if e != nil {
+		return nil, e
+	}
+
+	is, e := webfs.ListStatus(gowfs.Path{Name: name})
+	if e != nil {
+		return nil, e
+	}
+
+	ss := make([]os.FileInfo, 0, len(is))
+	for _, s := range is {
+		mode, _ := strconv.ParseUint(s.Permission, 8, 32)
+		ss = append(ss, &FileInfo{
+			name: s.PathSuffix,
+			size: s.Length,
+			mode: os.FileMode(mode),
+			time: s.ModificationTime,
+			dir:  (s.Type == "DIRECTORY"),
+		})
+	}
+	return ss, nil
+}
+func (webBackend) Mkdir(name string, perm os.FileMode) error {
+	if webfs == nil {
+		return errNoWebFS
 	}
+	_, e := webfs.MkDirs(gowfs.Path{Name: name}, perm)
+	return e
 }
+func (webBackend) MkdirAll(name string, perm os.FileMode) error {
+	return webBackend{}.Mkdir(name, perm)
+}
+func (webBackend) Remove(name string) error {
+	if webfs == nil {
+		return errNoWebFS
+	}
+	_, e := webfs.Delete(gowfs.Path{Name: name}, false)
+	return e
+}
+func (webBackend) RemoveAll(name string) error {
+	if webfs == nil {
+		return errNoWebFS
+	}
+	_, e := webfs.Delete(gowfs.Path{Name: name}, true)
+	return e
+}
+func (webBackend) Rename(oldname, newname string) error {
+	if webfs == nil {
+		return errNoWebFS
+	}
+	if isAncestor(oldname, newname) {
+		return ErrInvalidArgument
+	}
+	_, e := webfs.Rename(gowfs.Path{Name: oldname}, gowfs.Path{Name: newname})
+	return e
+}
+func (webBackend) Chmod(name string, mode os.FileMode) error {
+	if webfs == nil {
+		return errNoWebFS
+	}
+	_, e := webfs.SetPermission(gowfs.Path{Name: name}, mode)
+	return e
+}
+func (webBackend) Chtimes(name string, atime, mtime time.Time) error {
+	if webfs == nil {
+		return errNoWebFS
+	}
+	_, e := webfs.SetTimes(gowfs.Path{Name: name}, atime.Unix(), mtime.Unix())
+	return e
+}
+
+// unimplementedBackend registers a prefix without wiring up a real
+// client, so that paths under it fail with a clear error instead of
+// silently falling through to the local filesystem.
+type unimplementedBackend struct{ name string }
 
-// Create a directory, along with any necessary parents.  
+func (b unimplementedBackend) err() error {
+	return fmt.Errorf("fs: the %q backend is registered but not yet implemented", b.name)
+}
+func (b unimplementedBackend) Create(name string) (io.WriteCloser, error) { return nil, b.err() }
+func (b unimplementedBackend) Open(name string) (io.ReadCloser, error)    { return nil, b.err() }
+func (b unimplementedBackend) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	return nil, b.err()
+}
+func (b unimplementedBackend) Stat(name string) (os.FileInfo, error)      { return nil, b.err() }
+func (b unimplementedBackend) ReadDir(name string) ([]os.FileInfo, error) { return nil, b.err() }
+func (b unimplementedBackend) Mkdir(name string, perm os.FileMode) error  { return b.err() }
+func (b unimplementedBackend) MkdirAll(name string, perm os.FileMode) error {
+	return b.err()
+}
+func (b unimplementedBackend) Remove(name string) error                 { return b.err() }
+func (b unimplementedBackend) RemoveAll(name string) error               { return b.err() }
+func (b unimplementedBackend) Rename(oldname, newname string) error      { return b.err() }
+func (b unimplementedBackend) Chmod(name string, mode os.FileMode) error { return b.err() }
+func (b unimplementedBackend) Chtimes(name string, atime, mtime time.Time) error {
+	return b.err()
+}
+
+// Create opens name for writing on the backend registered for its
+// path prefix (the local filesystem if none matches).  Some backends,
+// such as WebHDFS and S3, return the writer end of a Go pipe whose
+// reader end a goroutine uploads.  If Create returns without error,
+// the caller is expected to write into the returned writer and then
+// close it to acknowledge the EOF.
+func Create(name string) (io.WriteCloser, error) {
+	return DefaultFs.Create(name)
+}
+
+func Open(name string) (io.ReadCloser, error) {
+	return DefaultFs.Open(name)
+}
+
+func ReadDir(name string) ([]os.FileInfo, error) {
+	return DefaultFs.ReadDir(name)
+}
+
+// Create a directory, along with any necessary parents.
 func Mkdir(name string) error {
-	switch fs, path := FsPath(name); fs {
-	case WebFS:
-		if webfs == nil {
-			return errNoWebFS
+	return DefaultFs.MkdirAll(name, 0777)
+}
+
+// Exists returns whether name exists, dispatching through DefaultFs
+// the same way Create/Open/... do.
+func Exists(name string) (bool, error) {
+	if _, e := DefaultFs.Stat(name); e != nil {
+		if os.IsNotExist(e) {
+			return false, nil
 		}
-		_, e := webfs.MkDirs(gowfs.Path{Name: path}, 0777)
-		return e
-	case HDFS:
-		return rpcfs.MkdirAll(path, 0777)
-	case InMem:
-		DefaultInMemFS.MkDir(path)
-		return nil
-	default:
-		return os.MkdirAll(path, 0777)
+		return false, e
 	}
+	return true, nil
 }
 
 // Put copy a local file to HDFS.  It overwrites if the destination
@@ -270,30 +660,5 @@ func Put(localFile, hdfsPath string) (bool, error) {
 }
 
 func Stat(name string) (os.FileInfo, error) {
-	switch fs, p := FsPath(name); fs {
-	case WebFS:
-		if webfs == nil {
-			return nil, errNoWebFS
-		}
-		if fs, e := webfs.GetFileStatus(gowfs.Path{Name: p}); e != nil {
-			return nil, &os.PathError{
-				Op:   "Stat",
-				Path: name + e.Error(), // BUG(y): Hacky way to return the real error.
-				Err:  os.ErrNotExist}   //BUG(y): for whatever error, returns os.PathError.
-		} else {
-			mode, _ := strconv.ParseUint(fs.Permission, 8, 32)
-			return &FileInfo{
-				name: path.Base(p),
-				size: fs.Length,
-				mode: os.FileMode(mode),
-				time: fs.ModificationTime,
-				dir:  fs.Type == "DIRECTORY"}, nil
-		}
-	case HDFS:
-		return rpcfs.Stat(p)
-	case InMem:
-		return DefaultInMemFS.Stat(p)
-	default:
-		return os.Stat(p)
-	}
+	return DefaultFs.Stat(name)
 }
@@ -0,0 +1,225 @@
+// Package fuse mounts the virtual paths of github.com/wangkuiyi/fs
+// (e.g. "/hdfs/...", "/inmem/...") as a real local directory, using
+// go-fuse's fs.InodeEmbedder API, so standard Unix tools can read and
+// write through any registered fs.Fs backend.
+package fuse
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	gofuse "github.com/hanwen/go-fuse/v2/fuse"
+
+	wfs "github.com/wangkuiyi/fs"
+)
+
+// MountOptions configures Mount. A zero value is a reasonable default.
+type MountOptions struct {
+	// Debug enables go-fuse's request tracing to stderr.
+	Debug bool
+}
+
+// Mount mounts rootURL (a virtual path resolved through wfs.DefaultFs,
+// e.g. "/hdfs/data") at mountpoint, and returns the running *fuse.Server.
+// Callers are expected to call Wait (or Unmount) on the returned
+// server when done.
+func Mount(mountpoint, rootURL string, opts *MountOptions) (*gofuse.Server, error) {
+	if opts == nil {
+		opts = &MountOptions{}
+	}
+	root := &inode{path: rootURL}
+	return fs.Mount(mountpoint, root, &fs.Options{
+		MountOptions: gofuse.MountOptions{Debug: opts.Debug},
+	})
+}
+
+// inode is a FUSE node backed by a single fully-qualified wfs path.
+// Every directory entry is looked up lazily against wfs, rather than
+// built once at mount time, so changes made outside the mount (e.g.
+// by another process writing to the same HDFS path) show up without
+// remounting.
+type inode struct {
+	fs.Inode
+	path string
+
+	// dirCacheMu guards invalidation of this inode's parent-dir entry
+	// cache after Create/Mkdir, working around WebHDFS's async
+	// visibility: a freshly created child may not show up in the
+	// parent's ListStatus for a short window.
+	dirCacheMu sync.Mutex
+}
+
+var (
+	_ fs.NodeLookuper  = (*inode)(nil)
+	_ fs.NodeGetattrer = (*inode)(nil)
+	_ fs.NodeReaddirer = (*inode)(nil)
+	_ fs.NodeOpener    = (*inode)(nil)
+	_ fs.NodeCreater   = (*inode)(nil)
+	_ fs.NodeMkdirer   = (*inode)(nil)
+	_ fs.NodeUnlinker  = (*inode)(nil)
+	_ fs.NodeRenamer   = (*inode)(nil)
+)
+
+func child(parent string, name string) string {
+	if parent == "/" {
+		return "/" + name
+	}
+	return parent + "/" + name
+}
+
+func (n *inode) Lookup(ctx context.Context, name string, out *gofuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	p := child(n.path, name)
+	fi, e := wfs.Stat(p)
+	if e != nil {
+		return nil, syscall.ENOENT
+	}
+	fillAttr(fi, &out.Attr)
+
+	mode := uint32(syscall.S_IFREG)
+	if fi.IsDir() {
+		mode = syscall.S_IFDIR
+	}
+	child := &inode{path: p}
+	return n.NewInode(ctx, child, fs.StableAttr{Mode: mode}), 0
+}
+
+func (n *inode) Getattr(ctx context.Context, f fs.FileHandle, out *gofuse.AttrOut) syscall.Errno {
+	fi, e := wfs.Stat(n.path)
+	if e != nil {
+		return syscall.ENOENT
+	}
+	fillAttr(fi, &out.Attr)
+	return 0
+}
+
+func fillAttr(fi os.FileInfo, attr *gofuse.Attr) {
+	attr.Size = uint64(fi.Size())
+	attr.Mode = uint32(fi.Mode().Perm())
+	if fi.IsDir() {
+		attr.Mode |= syscall.S_IFDIR
+	} else {
+		attr.Mode |= syscall.S_IFREG
+	}
+	attr.Mtime = uint64(fi.ModTime().Unix())
+}
+
+func (n *inode) Readdir(ctx context.Context) (fs.DirStream, syscall.Errno) {
+	ls, e := wfs.ReadDir(n.path)
+	if e != nil {
+		return nil, syscall.ENOENT
+	}
+	entries := make([]gofuse.DirEntry, 0, len(ls))
+	for _, fi := range ls {
+		mode := uint32(syscall.S_IFREG)
+		if fi.IsDir() {
+			mode = syscall.S_IFDIR
+		}
+		entries = append(entries, gofuse.DirEntry{Name: fi.Name(), Mode: mode})
+	}
+	return fs.NewListDirStream(entries), 0
+}
+
+func (n *inode) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if _, e := wfs.Stat(n.path); e != nil {
+		return nil, 0, syscall.ENOENT
+	}
+	return &fileHandle{path: n.path}, 0, 0
+}
+
+func (n *inode) Create(ctx context.Context, name string, flags uint32, mode uint32, out *gofuse.EntryOut) (*fs.Inode, fs.FileHandle, uint32, syscall.Errno) {
+	p := child(n.path, name)
+	w, e := wfs.Create(p)
+	if e != nil {
+		return nil, nil, 0, syscall.EIO
+	}
+	n.invalidateDirCache()
+	child := &inode{path: p}
+	return n.NewInode(ctx, child, fs.StableAttr{Mode: syscall.S_IFREG}), &fileHandle{w: w}, 0, 0
+}
+
+func (n *inode) Mkdir(ctx context.Context, name string, mode uint32, out *gofuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	p := child(n.path, name)
+	if e := wfs.Mkdir(p); e != nil {
+		return nil, syscall.EIO
+	}
+	n.invalidateDirCache()
+	child := &inode{path: p}
+	return n.NewInode(ctx, child, fs.StableAttr{Mode: syscall.S_IFDIR}), 0
+}
+
+func (n *inode) Unlink(ctx context.Context, name string) syscall.Errno {
+	if e := wfs.DefaultFs.Remove(child(n.path, name)); e != nil {
+		return syscall.EIO
+	}
+	return 0
+}
+
+func (n *inode) Rename(ctx context.Context, name string, newParent fs.InodeEmbedder, newName string, flags uint32) syscall.Errno {
+	np, ok := newParent.(*inode)
+	if !ok {
+		return syscall.EXDEV
+	}
+	if e := wfs.DefaultFs.Rename(child(n.path, name), child(np.path, newName)); e != nil {
+		return syscall.EIO
+	}
+	return 0
+}
+
+// invalidateDirCache drops go-fuse's cached attributes for n, so a
+// follow-up Lookup/Readdir re-fetches from wfs instead of serving a
+// stale pre-create listing during WebHDFS's async-visibility window.
+func (n *inode) invalidateDirCache() {
+	n.dirCacheMu.Lock()
+	defer n.dirCacheMu.Unlock()
+	n.NotifyContent(0, 0)
+}
+
+// fileHandle is a FUSE file handle backed by a virtual wfs path. Reads
+// open a fresh wfs.OpenRange per call rather than keeping a single
+// sequential reader around, since FUSE offsets aren't guaranteed to be
+// monotonic or gapless (random-access tools, pread, re-reads after a
+// forward seek). Writes still go through a single io.WriteCloser
+// opened at Create time, since no backend here supports seeking
+// writes.
+type fileHandle struct {
+	path string
+	w    io.WriteCloser
+}
+
+func (h *fileHandle) Read(ctx context.Context, dest []byte, off int64) (gofuse.ReadResult, syscall.Errno) {
+	if h.path == "" {
+		return nil, syscall.EBADF
+	}
+	r, e := wfs.OpenRange(h.path, off, int64(len(dest)))
+	if e != nil {
+		return nil, syscall.EIO
+	}
+	defer r.Close()
+	n, e := io.ReadFull(r, dest)
+	if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+		return nil, syscall.EIO
+	}
+	return gofuse.ReadResultData(dest[:n]), 0
+}
+
+func (h *fileHandle) Write(ctx context.Context, data []byte, off int64) (uint32, syscall.Errno) {
+	if h.w == nil {
+		return 0, syscall.EBADF
+	}
+	n, e := h.w.Write(data)
+	if e != nil {
+		return uint32(n), syscall.EIO
+	}
+	return uint32(n), 0
+}
+
+func (h *fileHandle) Release(ctx context.Context) syscall.Errno {
+	if h.w != nil {
+		h.w.Close()
+	}
+	return 0
+}
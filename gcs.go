@@ -0,0 +1,150 @@
+package fs
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// gcsFs implements Fs on top of Google Cloud Storage, the same way
+// s3Fs wraps S3: object keys have no real directories, so ReadDir and
+// Stat synthesize directory entries from common key prefixes.
+type gcsFs struct {
+	bucket *storage.BucketHandle
+}
+
+// HookupGCS registers a gcsFs backend under the "/gs/" prefix,
+// replacing the placeholder registered by this package's init,
+// backed by the given bucket.
+func HookupGCS(bucket string) error {
+	client, e := storage.NewClient(context.Background())
+	if e != nil {
+		return e
+	}
+	Register("/gs/", &gcsFs{bucket: client.Bucket(bucket)})
+	return nil
+}
+
+func (f *gcsFs) key(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func (f *gcsFs) Create(name string) (io.WriteCloser, error) {
+	return f.bucket.Object(f.key(name)).NewWriter(context.Background()), nil
+}
+
+func (f *gcsFs) Open(name string) (io.ReadCloser, error) {
+	return f.bucket.Object(f.key(name)).NewReader(context.Background())
+}
+
+func (f *gcsFs) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	return nil, ErrNotImplemented
+}
+
+// OpenRangeAt lets gcsFs double as a RangedFs, reading a byte range
+// without downloading the whole object first.
+func (f *gcsFs) OpenRangeAt(name string, off, n int64) (io.ReadCloser, error) {
+	return f.bucket.Object(f.key(name)).NewRangeReader(context.Background(), off, n)
+}
+
+func (f *gcsFs) Stat(name string) (os.FileInfo, error) {
+	key := f.key(name)
+	attrs, e := f.bucket.Object(key).Attrs(context.Background())
+	if e == nil {
+		return &FileInfo{
+			name: path.Base(key),
+			size: attrs.Size,
+			mode: 0644,
+			time: attrs.Updated.Unix(),
+		}, nil
+	}
+
+	it := f.bucket.Objects(context.Background(), &storage.Query{Prefix: key + "/"})
+	if _, e2 := it.Next(); e2 == nil {
+		return &FileInfo{name: path.Base(key), mode: os.ModeDir | 0755, dir: true}, nil
+	}
+	return nil, &os.PathError{Op: "Stat", Path: name, Err: os.ErrNotExist}
+}
+
+func (f *gcsFs) ReadDir(name string) ([]os.FileInfo, error) {
+	prefix := f.key(name)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	it := f.bucket.Objects(context.Background(), &storage.Query{Prefix: prefix, Delimiter: "/"})
+	ls := make([]os.FileInfo, 0)
+	for {
+		attrs, e := it.Next()
+		if e == iterator.Done {
+			break
+		}
+		if e != nil {
+			return nil, e
+		}
+		if attrs.Prefix != "" {
+			n := path.Base(strings.TrimSuffix(attrs.Prefix, "/"))
+			ls = append(ls, &FileInfo{name: n, mode: os.ModeDir | 0755, dir: true})
+			continue
+		}
+		ls = append(ls, &FileInfo{
+			name: path.Base(attrs.Name),
+			size: attrs.Size,
+			mode: 0644,
+			time: attrs.Updated.Unix(),
+		})
+	}
+	return ls, nil
+}
+
+// Mkdir synthesizes a directory the same way s3Fs does: a zero-byte
+// object under name+"/".
+func (f *gcsFs) Mkdir(name string, perm os.FileMode) error {
+	w := f.bucket.Object(f.key(name) + "/").NewWriter(context.Background())
+	return w.Close()
+}
+
+func (f *gcsFs) MkdirAll(name string, perm os.FileMode) error { return f.Mkdir(name, perm) }
+
+func (f *gcsFs) Remove(name string) error {
+	return f.bucket.Object(f.key(name)).Delete(context.Background())
+}
+
+func (f *gcsFs) RemoveAll(name string) error {
+	prefix := f.key(name)
+	it := f.bucket.Objects(context.Background(), &storage.Query{Prefix: prefix})
+	for {
+		attrs, e := it.Next()
+		if e == iterator.Done {
+			break
+		}
+		if e != nil {
+			return e
+		}
+		if e := f.bucket.Object(attrs.Name).Delete(context.Background()); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// Rename copies the object to its new key and deletes the old one;
+// GCS, like S3, has no atomic rename.
+func (f *gcsFs) Rename(oldname, newname string) error {
+	src := f.bucket.Object(f.key(oldname))
+	dst := f.bucket.Object(f.key(newname))
+	if _, e := dst.CopierFrom(src).Run(context.Background()); e != nil {
+		return e
+	}
+	return src.Delete(context.Background())
+}
+
+func (f *gcsFs) Chmod(name string, mode os.FileMode) error { return ErrNotImplemented }
+
+func (f *gcsFs) Chtimes(name string, atime, mtime time.Time) error { return ErrNotImplemented }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 )
 
 // If the key (string) has suffix '/', it denotes a directory;
@@ -16,6 +17,13 @@ type InMemFS map[string]*bytes.Buffer
 
 var (
 	DefaultInMemFS InMemFS = make(InMemFS)
+
+	// inMemModes and inMemTimes hold the permission bits and mtimes set
+	// via Chmod/Chtimes, keyed by name.  InMemFS itself stores only
+	// bytes, so entries with no Chmod/Chtimes call fall back to the
+	// defaults Stat always used before: 0777 and the zero time.
+	inMemModes = map[string]os.FileMode{}
+	inMemTimes = map[string]int64{}
 )
 
 type nopCloser struct {
@@ -88,11 +96,19 @@ func (im InMemFS) MkDir(name string) {
 
 func (im InMemFS) Stat(name string) (os.FileInfo, error) {
 	if _, ok := im[name]; ok {
+		mode := os.FileMode(0777)
+		if m, ok := inMemModes[name]; ok {
+			mode = m
+		}
+		var mtime int64
+		if t, ok := inMemTimes[name]; ok {
+			mtime = t
+		}
 		return &FileInfo{
 			name: path.Base(name),
 			size: int64(im[name].Len()),
-			mode: os.FileMode(0777),
-			time: 0,
+			mode: mode,
+			time: mtime,
 			dir:  name[len(name)-1] == '/'}, nil
 	}
 	return nil, &os.PathError{
@@ -100,3 +116,71 @@ func (im InMemFS) Stat(name string) (os.FileInfo, error) {
 		Path: name,
 		Err:  os.ErrNotExist}
 }
+
+// Remove deletes the entry named name.  It does not check whether a
+// directory entry still has entries under it; those are left in place.
+func (im InMemFS) Remove(name string) error {
+	if _, ok := im[name]; !ok {
+		return &os.PathError{Op: "Remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(im, name)
+	delete(inMemModes, name)
+	delete(inMemTimes, name)
+	return nil
+}
+
+// RemoveAll deletes name and, if it names a directory, every entry
+// under it.
+func (im InMemFS) RemoveAll(name string) error {
+	dir := name
+	if dir[len(dir)-1] != '/' {
+		dir += "/"
+	}
+	for k := range im {
+		if k == name || strings.HasPrefix(k, dir) {
+			delete(im, k)
+			delete(inMemModes, k)
+			delete(inMemTimes, k)
+		}
+	}
+	return nil
+}
+
+// Rename moves oldname to newname.  It rejects renaming a directory
+// into one of its own descendants.
+func (im InMemFS) Rename(oldname, newname string) error {
+	if isAncestor(oldname, newname) {
+		return ErrInvalidArgument
+	}
+	buf, ok := im[oldname]
+	if !ok {
+		return &os.PathError{Op: "Rename", Path: oldname, Err: os.ErrNotExist}
+	}
+	im[newname] = buf
+	delete(im, oldname)
+	if m, ok := inMemModes[oldname]; ok {
+		inMemModes[newname] = m
+		delete(inMemModes, oldname)
+	}
+	if t, ok := inMemTimes[oldname]; ok {
+		inMemTimes[newname] = t
+		delete(inMemTimes, oldname)
+	}
+	return nil
+}
+
+func (im InMemFS) Chmod(name string, mode os.FileMode) error {
+	if _, ok := im[name]; !ok {
+		return &os.PathError{Op: "Chmod", Path: name, Err: os.ErrNotExist}
+	}
+	inMemModes[name] = mode
+	return nil
+}
+
+func (im InMemFS) Chtimes(name string, atime, mtime time.Time) error {
+	if _, ok := im[name]; !ok {
+		return &os.PathError{Op: "Chtimes", Path: name, Err: os.ErrNotExist}
+	}
+	inMemTimes[name] = mtime.Unix()
+	return nil
+}
@@ -0,0 +1,49 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemFSRenameRemoveChmod(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs/%v", time.Now().UnixNano())
+	a := dir + "/a.txt"
+	b := dir + "/b.txt"
+
+	assert.Nil(Mkdir(dir))
+	w, e := Create(a)
+	assert.Nil(e)
+	w.Close()
+
+	assert.Nil(DefaultFs.Rename(a, b))
+	_, e = Stat(a)
+	assert.True(os.IsNotExist(e))
+	_, e = Stat(b)
+	assert.Nil(e)
+
+	assert.Nil(DefaultFs.Chmod(b, 0600))
+	fi, e := Stat(b)
+	assert.Nil(e)
+	assert.Equal(os.FileMode(0600), fi.Mode())
+
+	mtime := time.Unix(1000, 0)
+	assert.Nil(DefaultFs.Chtimes(b, mtime, mtime))
+	fi, e = Stat(b)
+	assert.Nil(e)
+	assert.Equal(mtime.Unix(), fi.ModTime().Unix())
+
+	assert.Nil(DefaultFs.Remove(b))
+	_, e = Stat(b)
+	assert.True(os.IsNotExist(e))
+}
+
+func TestRenameRejectsAncestorCycle(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(ErrInvalidArgument, DefaultFs.Rename("/inmem/a", "/inmem/a/b/c"))
+}
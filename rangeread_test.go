@@ -0,0 +1,34 @@
+package fs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenRange(t *testing.T) {
+	assert := assert.New(t)
+
+	name := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs/%v/range.txt", time.Now().UnixNano())
+	w, e := Create(name)
+	assert.Nil(e)
+	fmt.Fprint(w, "0123456789")
+	w.Close()
+
+	r, e := OpenRange(name, 3, 4)
+	assert.Nil(e)
+	b, e := ioutil.ReadAll(r)
+	assert.Nil(e)
+	r.Close()
+	assert.Equal("3456", string(b))
+
+	r, e = OpenRange(name, 8, -1)
+	assert.Nil(e)
+	b, e = ioutil.ReadAll(r)
+	assert.Nil(e)
+	r.Close()
+	assert.Equal("89", string(b))
+}
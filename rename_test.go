@@ -0,0 +1,40 @@
+package fs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrossBackendRenameRequiresOptIn(t *testing.T) {
+	assert := assert.New(t)
+
+	src := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs/%v/src.txt", time.Now().UnixNano())
+	w, e := Create(src)
+	assert.Nil(e)
+	fmt.Fprint(w, "cross-backend")
+	w.Close()
+
+	dir, e := ioutil.TempDir("", "fs-rename-dest")
+	assert.Nil(e)
+	defer os.RemoveAll(dir)
+	dst := dir + "/dst.txt"
+
+	e = DefaultFs.Rename(src, dst)
+	assert.NotNil(e) // disabled by default
+
+	AllowCrossBackendRename = true
+	defer func() { AllowCrossBackendRename = false }()
+
+	assert.Nil(DefaultFs.Rename(src, dst))
+	_, e = Stat(src)
+	assert.True(os.IsNotExist(e))
+
+	b, e := ioutil.ReadFile(dst)
+	assert.Nil(e)
+	assert.Equal("cross-backend", string(b))
+}
@@ -0,0 +1,209 @@
+package fs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// s3Fs implements Fs on top of an S3-compatible object store.  Object
+// keys have no real directories, so ReadDir and Mkdir synthesize
+// directory entries from common key prefixes, the way goofys does.
+type s3Fs struct {
+	bucket string
+	client *s3.S3
+	up     *s3manager.Uploader
+}
+
+// HookupS3 registers an s3Fs backend under the "/s3/" prefix, backed
+// by bucket in region.  Credentials are resolved the usual AWS SDK
+// way (environment, shared config file, or instance role).
+func HookupS3(bucket, region string) error {
+	sess, e := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if e != nil {
+		return e
+	}
+	Register("/s3/", &s3Fs{
+		bucket: bucket,
+		client: s3.New(sess),
+		up:     s3manager.NewUploader(sess),
+	})
+	return nil
+}
+
+func (f *s3Fs) key(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// Create streams a PutObject through the Create-returns-a-pipe
+// pattern already used for WebHDFS, so callers can write to the
+// returned WriteCloser without buffering the whole object.
+func (f *s3Fs) Create(name string) (io.WriteCloser, error) {
+	r, w := io.Pipe()
+	key := f.key(name)
+	go func() {
+		_, e := f.up.Upload(&s3manager.UploadInput{
+			Bucket: aws.String(f.bucket),
+			Key:    aws.String(key),
+			Body:   r,
+		})
+		r.CloseWithError(e)
+	}()
+	return w, nil
+}
+
+func (f *s3Fs) Open(name string) (io.ReadCloser, error) {
+	out, e := f.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(f.key(name)),
+	})
+	if e != nil {
+		return nil, e
+	}
+	return out.Body, nil
+}
+
+func (f *s3Fs) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	return nil, ErrNotImplemented
+}
+
+func (f *s3Fs) Stat(name string) (os.FileInfo, error) {
+	key := f.key(name)
+	head, e := f.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(key),
+	})
+	if e == nil {
+		return &FileInfo{
+			name: path.Base(key),
+			size: aws.Int64Value(head.ContentLength),
+			mode: 0644,
+			time: aws.TimeValue(head.LastModified).Unix(),
+		}, nil
+	}
+
+	// HeadObject on a bare key fails for "directories", which exist
+	// only as a common prefix of other keys.  Treat a non-empty
+	// listing under key+"/" as a synthesized directory.
+	out, e2 := f.client.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket:  aws.String(f.bucket),
+		Prefix:  aws.String(key + "/"),
+		MaxKeys: aws.Int64(1),
+	})
+	if e2 != nil || len(out.Contents) == 0 {
+		return nil, &os.PathError{Op: "Stat", Path: name, Err: os.ErrNotExist}
+	}
+	return &FileInfo{name: path.Base(key), mode: os.ModeDir | 0755, dir: true}, nil
+}
+
+func (f *s3Fs) ReadDir(name string) ([]os.FileInfo, error) {
+	prefix := f.key(name)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	seen := map[string]os.FileInfo{}
+	e := f.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket:    aws.String(f.bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, last bool) bool {
+		for _, p := range page.CommonPrefixes {
+			n := path.Base(strings.TrimSuffix(aws.StringValue(p.Prefix), "/"))
+			seen[n] = &FileInfo{name: n, mode: os.ModeDir | 0755, dir: true}
+		}
+		for _, o := range page.Contents {
+			n := path.Base(aws.StringValue(o.Key))
+			seen[n] = &FileInfo{
+				name: n,
+				size: aws.Int64Value(o.Size),
+				mode: 0644,
+				time: aws.TimeValue(o.LastModified).Unix(),
+			}
+		}
+		return true
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	ls := make([]os.FileInfo, 0, len(seen))
+	for _, fi := range seen {
+		ls = append(ls, fi)
+	}
+	return ls, nil
+}
+
+// Mkdir synthesizes a directory by writing a zero-byte object under
+// name+"/", the same convention S3 consoles and goofys use.
+func (f *s3Fs) Mkdir(name string, perm os.FileMode) error {
+	_, e := f.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(f.key(name) + "/"),
+		Body:   bytes.NewReader(nil),
+	})
+	return e
+}
+
+func (f *s3Fs) MkdirAll(name string, perm os.FileMode) error {
+	return f.Mkdir(name, perm)
+}
+
+func (f *s3Fs) Remove(name string) error {
+	_, e := f.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(f.key(name)),
+	})
+	return e
+}
+
+func (f *s3Fs) RemoveAll(name string) error {
+	prefix := f.key(name)
+	var objs []*s3.ObjectIdentifier
+	e := f.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(f.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, last bool) bool {
+		for _, o := range page.Contents {
+			objs = append(objs, &s3.ObjectIdentifier{Key: o.Key})
+		}
+		return true
+	})
+	if e != nil {
+		return e
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+	_, e = f.client.DeleteObjects(&s3.DeleteObjectsInput{
+		Bucket: aws.String(f.bucket),
+		Delete: &s3.Delete{Objects: objs},
+	})
+	return e
+}
+
+// Rename copies the object to its new key and deletes the old one; S3
+// has no atomic rename.
+func (f *s3Fs) Rename(oldname, newname string) error {
+	src := f.bucket + "/" + f.key(oldname)
+	if _, e := f.client.CopyObject(&s3.CopyObjectInput{
+		Bucket:     aws.String(f.bucket),
+		CopySource: aws.String(src),
+		Key:        aws.String(f.key(newname)),
+	}); e != nil {
+		return e
+	}
+	return f.Remove(oldname)
+}
+
+func (f *s3Fs) Chmod(name string, mode os.FileMode) error { return ErrNotImplemented }
+
+func (f *s3Fs) Chtimes(name string, atime, mtime time.Time) error { return ErrNotImplemented }
@@ -0,0 +1,226 @@
+package fs
+
+import (
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// whiteoutPrefix marks a name as deleted in a lower layer, following
+// the classic unionfs/aufs convention: a file "a/b" is hidden once
+// "a/.wh.b" exists in some layer above it.
+const whiteoutPrefix = ".wh."
+
+// UnionFS composes an ordered list of Fs layers into a single Fs:
+// reads fall through layers top to bottom, returning the first hit,
+// while every write lands in Layers[0], the writable layer. Modifying
+// a file that only exists in a lower, read-only layer copies it up to
+// Layers[0] first; removing it instead writes a whiteout marker so
+// the lower copy stays hidden without needing to touch the read-only
+// layer at all.
+//
+// This lets a process "edit" files that live on HDFS or S3 without
+// round-tripping the whole blob, or layer an InMemFS on top of a real
+// backend for hermetic tests.
+type UnionFS struct {
+	// Layers lists the union's branches, topmost (writable) first.
+	Layers []Fs
+}
+
+// NewUnionFS returns a UnionFS with writable on top of the given
+// read-only layers.
+func NewUnionFS(writable Fs, readOnly ...Fs) *UnionFS {
+	return &UnionFS{Layers: append([]Fs{writable}, readOnly...)}
+}
+
+func whiteoutName(name string) string {
+	return path.Join(path.Dir(name), whiteoutPrefix+path.Base(name))
+}
+
+func (u *UnionFS) top() Fs { return u.Layers[0] }
+
+func (u *UnionFS) whitedOut(name string) bool {
+	_, e := u.top().Stat(whiteoutName(name))
+	return e == nil
+}
+
+// copyUp copies name from the first layer that has it into the
+// writable top layer, so subsequent writes never touch a read-only
+// backend.
+func (u *UnionFS) copyUp(name string) error {
+	if _, e := u.top().Stat(name); e == nil {
+		return nil // already in the writable layer
+	}
+	for _, l := range u.Layers[1:] {
+		r, e := l.Open(name)
+		if e != nil {
+			continue
+		}
+		defer r.Close()
+		w, e := u.top().Create(name)
+		if e != nil {
+			return e
+		}
+		defer w.Close()
+		_, e = io.Copy(w, r)
+		return e
+	}
+	return &os.PathError{Op: "copyUp", Path: name, Err: os.ErrNotExist}
+}
+
+func (u *UnionFS) Create(name string) (io.WriteCloser, error) {
+	if u.whitedOut(name) {
+		u.top().Remove(whiteoutName(name))
+	}
+	return u.top().Create(name)
+}
+
+func (u *UnionFS) Open(name string) (io.ReadCloser, error) {
+	if u.whitedOut(name) {
+		return nil, &os.PathError{Op: "Open", Path: name, Err: os.ErrNotExist}
+	}
+	var last error
+	for _, l := range u.Layers {
+		r, e := l.Open(name)
+		if e == nil {
+			return r, nil
+		}
+		last = e
+	}
+	return nil, last
+}
+
+func (u *UnionFS) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		if e := u.copyUp(name); e != nil && !os.IsNotExist(e) {
+			return nil, e
+		}
+		return u.top().OpenFile(name, flag, perm)
+	}
+	if u.whitedOut(name) {
+		return nil, &os.PathError{Op: "OpenFile", Path: name, Err: os.ErrNotExist}
+	}
+	var last error
+	for _, l := range u.Layers {
+		rw, e := l.OpenFile(name, flag, perm)
+		if e == nil {
+			return rw, nil
+		}
+		last = e
+	}
+	return nil, last
+}
+
+func (u *UnionFS) Stat(name string) (os.FileInfo, error) {
+	if u.whitedOut(name) {
+		return nil, &os.PathError{Op: "Stat", Path: name, Err: os.ErrNotExist}
+	}
+	var last error
+	for _, l := range u.Layers {
+		fi, e := l.Stat(name)
+		if e == nil {
+			return fi, nil
+		}
+		last = e
+	}
+	return nil, last
+}
+
+// ReadDir merges the directory listing of every layer, deduping names
+// (the topmost layer's entry wins) and hiding anything whited out.
+func (u *UnionFS) ReadDir(name string) ([]os.FileInfo, error) {
+	merged := map[string]os.FileInfo{}
+	whiteouts := map[string]bool{}
+	found := false
+
+	for _, l := range u.Layers {
+		ls, e := l.ReadDir(name)
+		if e != nil {
+			continue
+		}
+		found = true
+		for _, fi := range ls {
+			if strings.HasPrefix(fi.Name(), whiteoutPrefix) {
+				whiteouts[strings.TrimPrefix(fi.Name(), whiteoutPrefix)] = true
+				continue
+			}
+			if _, ok := merged[fi.Name()]; !ok {
+				merged[fi.Name()] = fi
+			}
+		}
+	}
+	if !found {
+		return nil, &os.PathError{Op: "ReadDir", Path: name, Err: os.ErrNotExist}
+	}
+
+	ls := make([]os.FileInfo, 0, len(merged))
+	for n, fi := range merged {
+		if !whiteouts[n] {
+			ls = append(ls, fi)
+		}
+	}
+	return ls, nil
+}
+
+func (u *UnionFS) Mkdir(name string, perm os.FileMode) error {
+	if u.whitedOut(name) {
+		u.top().Remove(whiteoutName(name))
+	}
+	return u.top().Mkdir(name, perm)
+}
+
+func (u *UnionFS) MkdirAll(name string, perm os.FileMode) error {
+	if u.whitedOut(name) {
+		u.top().Remove(whiteoutName(name))
+	}
+	return u.top().MkdirAll(name, perm)
+}
+
+// Remove hides name with a whiteout marker unless it only exists in
+// the writable layer, in which case it is removed outright.
+func (u *UnionFS) Remove(name string) error {
+	if _, e := u.top().Stat(name); e == nil {
+		if e := u.top().Remove(name); e != nil {
+			return e
+		}
+	}
+	if _, e := u.Stat(name); e == nil {
+		u.top().MkdirAll(path.Dir(name), 0777)
+		w, e := u.top().Create(whiteoutName(name))
+		if e != nil {
+			return e
+		}
+		return w.Close()
+	}
+	return nil
+}
+
+func (u *UnionFS) RemoveAll(name string) error {
+	return u.Remove(name)
+}
+
+func (u *UnionFS) Rename(oldname, newname string) error {
+	if e := u.copyUp(oldname); e != nil && !os.IsNotExist(e) {
+		return e
+	}
+	if e := u.top().Rename(oldname, newname); e != nil {
+		return e
+	}
+	return u.Remove(oldname)
+}
+
+func (u *UnionFS) Chmod(name string, mode os.FileMode) error {
+	if e := u.copyUp(name); e != nil {
+		return e
+	}
+	return u.top().Chmod(name, mode)
+}
+
+func (u *UnionFS) Chtimes(name string, atime, mtime time.Time) error {
+	if e := u.copyUp(name); e != nil {
+		return e
+	}
+	return u.top().Chtimes(name, atime, mtime)
+}
@@ -0,0 +1,112 @@
+package fs
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rootedFs prefixes every path with root before delegating to under.
+// It lets two temp directories stand in for two independent backends
+// in TestUnionFS below.
+type rootedFs struct {
+	root  string
+	under Fs
+}
+
+func (r *rootedFs) full(name string) string { return path.Join(r.root, name) }
+
+func (r *rootedFs) Create(name string) (io.WriteCloser, error) { return r.under.Create(r.full(name)) }
+func (r *rootedFs) Open(name string) (io.ReadCloser, error)    { return r.under.Open(r.full(name)) }
+func (r *rootedFs) OpenFile(name string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	return r.under.OpenFile(r.full(name), flag, perm)
+}
+func (r *rootedFs) Stat(name string) (os.FileInfo, error)      { return r.under.Stat(r.full(name)) }
+func (r *rootedFs) ReadDir(name string) ([]os.FileInfo, error) { return r.under.ReadDir(r.full(name)) }
+func (r *rootedFs) Mkdir(name string, perm os.FileMode) error  { return r.under.Mkdir(r.full(name), perm) }
+func (r *rootedFs) MkdirAll(name string, perm os.FileMode) error {
+	return r.under.MkdirAll(r.full(name), perm)
+}
+func (r *rootedFs) Remove(name string) error    { return r.under.Remove(r.full(name)) }
+func (r *rootedFs) RemoveAll(name string) error { return r.under.RemoveAll(r.full(name)) }
+func (r *rootedFs) Rename(oldname, newname string) error {
+	return r.under.Rename(r.full(oldname), r.full(newname))
+}
+func (r *rootedFs) Chmod(name string, mode os.FileMode) error {
+	return r.under.Chmod(r.full(name), mode)
+}
+func (r *rootedFs) Chtimes(name string, atime, mtime time.Time) error {
+	return r.under.Chtimes(r.full(name), atime, mtime)
+}
+
+// rootedLocalFs returns an Fs rooted at dir, backed by the real
+// filesystem, for use as a UnionFS layer in tests.
+func rootedLocalFs(dir string) Fs {
+	return &rootedFs{root: dir, under: localBackend{}}
+}
+
+func TestUnionFS(t *testing.T) {
+	assert := assert.New(t)
+
+	lowerDir, e := ioutil.TempDir("", "fs-unionfs-lower")
+	assert.Nil(e)
+	defer os.RemoveAll(lowerDir)
+	upperDir, e := ioutil.TempDir("", "fs-unionfs-upper")
+	assert.Nil(e)
+	defer os.RemoveAll(upperDir)
+
+	lower := rootedLocalFs(lowerDir)
+	upper := rootedLocalFs(upperDir)
+	u := NewUnionFS(upper, lower)
+
+	// A file that exists only in the lower, read-only layer is visible
+	// through the union.
+	assert.Nil(lower.Mkdir("/a", 0777))
+	w, e := lower.Create("/a/hello.txt")
+	assert.Nil(e)
+	w.Write([]byte("hello"))
+	w.Close()
+
+	r, e := u.Open("/a/hello.txt")
+	assert.Nil(e)
+	b, _ := ioutil.ReadAll(r)
+	r.Close()
+	assert.Equal("hello", string(b))
+
+	// OpenFile with a read-only flag must fall through to the lower
+	// layer too, just like Open.
+	rw, e := u.OpenFile("/a/hello.txt", os.O_RDONLY, 0)
+	assert.Nil(e)
+	b, _ = ioutil.ReadAll(rw)
+	rw.Close()
+	assert.Equal("hello", string(b))
+
+	// Removing it through the union must not touch the lower layer,
+	// and must hide the file from further reads and ReadDir.
+	assert.Nil(u.Remove("/a/hello.txt"))
+	_, e = lower.Stat("/a/hello.txt")
+	assert.Nil(e) // still present underneath
+
+	_, e = u.Open("/a/hello.txt")
+	assert.True(os.IsNotExist(e))
+
+	ls, e := u.ReadDir("/a")
+	assert.Nil(e)
+	assert.Equal(0, len(ls))
+
+	// Writing a new file lands in the writable upper layer only.
+	w, e = u.Create("/a/world.txt")
+	assert.Nil(e)
+	w.Write([]byte("world"))
+	w.Close()
+
+	_, e = lower.Stat("/a/world.txt")
+	assert.True(os.IsNotExist(e))
+	_, e = upper.Stat("/a/world.txt")
+	assert.Nil(e)
+}
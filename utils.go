@@ -1,21 +1,72 @@
 package fs
 
-import "encoding/gob"
-
+// Save writes data to filename, picking a Codec and Compression from
+// its suffix: ".json" selects JSONCodec, ".pb" selects ProtoCodec
+// when data implements proto.Message, and everything else falls back
+// to the GobCodec Save always used; a trailing ".gz" or ".zst" wraps
+// the result in that compression regardless of the codec chosen.
 func Save(filename string, data interface{}) error {
+	return SaveAs(filename, data, codecForSuffix(stripCompressionSuffix(filename), data))
+}
+
+// Load reads filename written by Save, inferring the same Codec and
+// Compression from its suffix.
+func Load(filename string, data interface{}) error {
+	return LoadAs(filename, data, codecForSuffix(stripCompressionSuffix(filename), data))
+}
+
+func stripCompressionSuffix(name string) string {
+	_, stripped := compressionForSuffix(name)
+	return stripped
+}
+
+// SaveAs writes data to filename using codec, optionally compressed.
+// The compression is inferred from filename's ".gz"/".zst" suffix
+// unless overridden with WithCompression.
+func SaveAs(filename string, data interface{}, codec Codec, opts ...Option) error {
+	compression, _ := compressionForSuffix(filename)
+	o := codecOptions{compression: compression}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	f, e := Create(filename)
 	if e != nil {
 		return e
 	}
 	defer f.Close()
-	return gob.NewEncoder(f).Encode(data)
+
+	w, e := compressWriter(f, o.compression)
+	if e != nil {
+		return e
+	}
+	if e := codec.Encode(w, data); e != nil {
+		w.Close()
+		return e
+	}
+	return w.Close()
 }
 
-func Load(filename string, data interface{}) error {
+// LoadAs reads filename written by SaveAs using codec, inferring
+// compression the same way SaveAs does unless overridden with
+// WithCompression.
+func LoadAs(filename string, data interface{}, codec Codec, opts ...Option) error {
+	compression, _ := compressionForSuffix(filename)
+	o := codecOptions{compression: compression}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	f, e := Open(filename)
 	if e != nil {
 		return e
 	}
 	defer f.Close()
-	return gob.NewDecoder(f).Decode(data)
+
+	r, e := decompressReader(f, o.compression)
+	if e != nil {
+		return e
+	}
+	defer r.Close()
+	return codec.Decode(r, data)
 }
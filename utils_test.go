@@ -22,7 +22,66 @@ func TestSaveAndLoad(t *testing.T) {
 
 	assert.Nil(Save(filename, save))
 
-	load, e := Load(filename, &T{})
+	load := &T{}
+	assert.Nil(Load(filename, load))
 	assert.Equal(load, &T{Name: "Yi", Age: 36})
-	assert.Nil(e)
+}
+
+func TestSaveAndLoadJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	type T struct {
+		Name string
+		Age  int
+	}
+	save := &T{Name: "Yi", Age: 36}
+
+	filename := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs-%v/saveLoad.json",
+		time.Now().UnixNano())
+
+	assert.Nil(Save(filename, save))
+
+	load := &T{}
+	assert.Nil(Load(filename, load))
+	assert.Equal(save, load)
+}
+
+func TestSaveAndLoadGzip(t *testing.T) {
+	assert := assert.New(t)
+
+	type T struct {
+		Name string
+		Age  int
+	}
+	save := &T{Name: "Yi", Age: 36}
+
+	filename := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs-%v/saveLoad.json.gz",
+		time.Now().UnixNano())
+
+	assert.Nil(Save(filename, save))
+
+	load := &T{}
+	assert.Nil(Load(filename, load))
+	assert.Equal(save, load)
+}
+
+func TestSaveAsOverridesCompression(t *testing.T) {
+	assert := assert.New(t)
+
+	type T struct {
+		Name string
+		Age  int
+	}
+	save := &T{Name: "Yi", Age: 36}
+
+	// No ".gz" suffix, but WithCompression(Gzip) still applies it on
+	// both ends.
+	filename := fmt.Sprintf("/inmem/test/github.com/wangkuiyi/fs-%v/saveLoad.bin",
+		time.Now().UnixNano())
+
+	assert.Nil(SaveAs(filename, save, GobCodec{}, WithCompression(Gzip)))
+
+	load := &T{}
+	assert.Nil(LoadAs(filename, load, GobCodec{}, WithCompression(Gzip)))
+	assert.Equal(save, load)
 }